@@ -0,0 +1,36 @@
+package glob
+
+import "regexp"
+
+// Regexp is the minimal surface Glob needs from a compiled regular
+// expression. *regexp.Regexp already satisfies it; other engines, such as
+// the PCRE engine, provide a small adapter.
+type Regexp interface {
+	MatchString(s string) bool
+	FindStringSubmatch(s string) []string
+	NumSubexp() int
+}
+
+// Engine compiles the regex fragment produced by the glob compiler into a
+// Regexp.
+type Engine interface {
+	Compile(expr string) (Regexp, error)
+}
+
+var engines = map[string]Engine{
+	"regexp": stdlibEngine{},
+}
+
+// RegisterEngine makes a named Engine available to Compile. The PCRE engine
+// registers itself this way from an init function guarded by the pcre
+// build tag, so that pulling in cgo and the PCRE binding is opt-in rather
+// than a hard dependency of this package.
+func RegisterEngine(name string, e Engine) {
+	engines[name] = e
+}
+
+type stdlibEngine struct{}
+
+func (stdlibEngine) Compile(expr string) (Regexp, error) {
+	return regexp.Compile(expr)
+}
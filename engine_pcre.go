@@ -0,0 +1,60 @@
+//go:build pcre
+
+package glob
+
+import (
+	"errors"
+
+	"github.com/glenn-brown/golang-pkg-pcre/src/pkg/pcre"
+)
+
+func init() {
+	RegisterEngine("pcre", pcreEngine{})
+}
+
+// pcreEngine compiles patterns that need extended-glob capture features
+// (backreferences, possessive quantifiers, ...) that the stdlib regexp
+// engine can't express. It is opt-in via the pcre build tag because it
+// pulls in cgo.
+type pcreEngine struct{}
+
+func (pcreEngine) Compile(expr string) (Regexp, error) {
+	p, err := pcre.Compile(expr, 0)
+	if err != nil {
+		return nil, errors.New(err.String())
+	}
+	return &pcreRegexp{p: &p}, nil
+}
+
+// pcreRegexp adapts *pcre.Regexp to the Regexp interface.
+type pcreRegexp struct {
+	p *pcre.Regexp
+}
+
+func (r *pcreRegexp) MatchString(s string) bool {
+	m := r.p.MatcherString(s, 0)
+	return m.Matches()
+}
+
+func (r *pcreRegexp) FindStringSubmatch(s string) []string {
+	m := r.p.MatcherString(s, 0)
+	if !m.Matches() {
+		return nil
+	}
+	num := m.Groups()
+	groups := make([]string, 0, num+1)
+	for i := 0; i <= num; i++ {
+		groups = append(groups, m.GroupString(i))
+	}
+	return groups
+}
+
+func (r *pcreRegexp) NumSubexp() int {
+	return r.p.Groups()
+}
+
+// FindIndex lets pcreRegexp satisfy the byte-offset lookup Glob.FindReaderIndex
+// falls back to for engines without native reader support.
+func (r *pcreRegexp) FindIndex(b []byte) []int {
+	return r.p.FindIndex(b, 0)
+}
@@ -0,0 +1,45 @@
+package glob
+
+import "testing"
+
+func TestPcreConstruct(t *testing.T) {
+	tests := []struct {
+		regex string
+		want  string
+	}{
+		{`(foo)\1`, "a backreference"},
+		{`foo(?=bar)`, "a lookahead"},
+		{`foo(?!bar)`, "a lookahead"},
+		{`(?<=foo)bar`, "a lookbehind"},
+		{`(?<!foo)bar`, "a lookbehind"},
+		{`(?>foo)`, "an atomic group"},
+		{`fo*+`, "a possessive quantifier"},
+		{`foo`, "an extended-glob capture feature"},
+	}
+	for _, tt := range tests {
+		if got := pcreConstruct(tt.regex); got != tt.want {
+			t.Errorf("pcreConstruct(%q) = %q, want %q", tt.regex, got, tt.want)
+		}
+	}
+}
+
+func TestStdlibEngineCompile(t *testing.T) {
+	e := stdlibEngine{}
+	re, err := e.Compile("^foo.*bar$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("foobazbar") {
+		t.Error("MatchString(\"foobazbar\") = false, want true")
+	}
+	if _, err := e.Compile("("); err == nil {
+		t.Error("Compile of an unbalanced regex: want error, got nil")
+	}
+}
+
+func TestRegisterEngine(t *testing.T) {
+	RegisterEngine("glob-test-engine", stdlibEngine{})
+	if _, ok := engines["glob-test-engine"]; !ok {
+		t.Error("RegisterEngine did not register under the given name")
+	}
+}
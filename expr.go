@@ -0,0 +1,266 @@
+package glob
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Matcher is implemented by anything that can test a fixture string for a
+// match, so that Glob and Expr can be composed and passed around
+// interchangeably.
+type Matcher interface {
+	Match(fixture string) bool
+}
+
+// Expr is a compiled boolean expression over glob patterns, built by
+// CompileExpr. It supports `&&`, `||`, `!` and parenthesised
+// sub-expressions, with leaf tokens being glob patterns compiled with the
+// existing Compile.
+type Expr struct {
+	root    exprNode
+	strings []string
+}
+
+// CompileExpr parses expr as a boolean expression of glob patterns, e.g.
+// `(*.go || *.py) && !vendor/**`, and compiles every leaf pattern with
+// Compile using separators.
+func CompileExpr(expr string, separators ...rune) (*Expr, error) {
+	p := &exprParser{input: expr, separators: separators}
+	p.next()
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok != tokEOF {
+		return nil, fmt.Errorf("glob: unexpected %q in expression %q", p.tokText, expr)
+	}
+	return &Expr{root: root, strings: p.leaves}, nil
+}
+
+// MustCompileExpr is the same as CompileExpr, except that if CompileExpr
+// returns an error, this will panic.
+func MustCompileExpr(expr string, separators ...rune) *Expr {
+	e, err := CompileExpr(expr, separators...)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Match reports whether fixture satisfies the compiled expression.
+func (e *Expr) Match(fixture string) bool {
+	return e.root.eval(fixture)
+}
+
+// Strings returns the glob patterns referenced by the expression, in the
+// order they appear.
+func (e *Expr) Strings() []string {
+	return e.strings
+}
+
+type exprNode interface {
+	eval(fixture string) bool
+}
+
+type leafNode struct {
+	glob *Glob
+}
+
+func (n *leafNode) eval(fixture string) bool { return n.glob.Match(fixture) }
+
+type notNode struct{ x exprNode }
+
+func (n *notNode) eval(fixture string) bool { return !n.x.eval(fixture) }
+
+type andNode struct{ l, r exprNode }
+
+func (n *andNode) eval(fixture string) bool { return n.l.eval(fixture) && n.r.eval(fixture) }
+
+type orNode struct{ l, r exprNode }
+
+func (n *orNode) eval(fixture string) bool { return n.l.eval(fixture) || n.r.eval(fixture) }
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+	tokPattern
+)
+
+// exprParser is a small recursive-descent parser for boolean glob
+// expressions: orExpr := andExpr { "||" andExpr }, andExpr := unary { "&&"
+// unary }, unary := "!" unary | primary, primary := "(" orExpr ")" | pattern.
+type exprParser struct {
+	input      string
+	pos        int
+	separators []rune
+	leaves     []string
+
+	tok     tokKind
+	tokText string
+}
+
+func (p *exprParser) next() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(p.input) {
+		p.tok, p.tokText = tokEOF, ""
+		return
+	}
+	switch {
+	case strings.HasPrefix(p.input[p.pos:], "&&"):
+		p.tok, p.tokText, p.pos = tokAnd, "&&", p.pos+2
+	case strings.HasPrefix(p.input[p.pos:], "||"):
+		p.tok, p.tokText, p.pos = tokOr, "||", p.pos+2
+	case p.input[p.pos] == '!':
+		p.tok, p.tokText, p.pos = tokNot, "!", p.pos+1
+	case p.input[p.pos] == '(':
+		p.tok, p.tokText, p.pos = tokLParen, "(", p.pos+1
+	case p.input[p.pos] == ')':
+		p.tok, p.tokText, p.pos = tokRParen, ")", p.pos+1
+	default:
+		// Read a pattern leaf, tracking bracket classes ([...]) and
+		// parenthesised groups (the extended-glob `@(`, `*(`, `+(`, `?(`
+		// forms and plain `(...)`) so that glob syntax embedded in the
+		// leaf isn't mistaken for expression operators. A bare '!' is
+		// only ever a NOT operator at the token boundary handled above,
+		// so it never delimits a pattern here, and this lets a leaf
+		// contain one literally (e.g. "CHANGELOG!.md" or "[!a-z]*.go").
+		start := p.pos
+		depth := 0
+		inBracket := false
+	scan:
+		for p.pos < len(p.input) {
+			c := p.input[p.pos]
+			switch {
+			case c == '\\' && p.pos+1 < len(p.input):
+				p.pos += 2
+			case inBracket:
+				inBracket = c != ']'
+				p.pos++
+			case c == '[':
+				inBracket = true
+				p.pos++
+			case c == '(':
+				depth++
+				p.pos++
+			case c == ')' && depth > 0:
+				depth--
+				p.pos++
+			case depth > 0:
+				p.pos++
+			case c == ')' || c == ' ':
+				break scan
+			case (c == '&' || c == '|') && p.pos+1 < len(p.input) && p.input[p.pos+1] == c:
+				break scan
+			default:
+				p.pos++
+			}
+		}
+		p.tok, p.tokText = tokPattern, p.input[start:p.pos]
+	}
+}
+
+// peekBareExtGlob reports whether the parser is sitting on a "(" that opens
+// and closes around a single pattern leaf containing a bare '|', e.g.
+// "(foo|bar)". The parentheses are read as expression grouping, and per the
+// documented grammar an unescaped '|' outside a paren-delimited group is
+// just a literal character, so the leaf "foo|bar" would silently compile to
+// a literal match for the 7-byte string "foo|bar" instead of the
+// alternation the syntax suggests — almost certainly a
+// bare extended-glob group written without its required prefix. Parser
+// state is restored before returning, regardless of the outcome.
+func (p *exprParser) peekBareExtGlob() (leaf string, ok bool) {
+	save := *p
+	defer func() { *p = save }()
+	p.next()
+	if p.tok != tokPattern || !strings.Contains(p.tokText, "|") {
+		return "", false
+	}
+	leaf = p.tokText
+	p.next()
+	return leaf, p.tok == tokRParen
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.tok == tokNot {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	switch p.tok {
+	case tokLParen:
+		if leaf, ok := p.peekBareExtGlob(); ok {
+			return nil, fmt.Errorf("glob: %q in expression %q looks like an extended-glob group; write \"@(%s)\" to use alternation inside an expression", "("+leaf+")", p.input, leaf)
+		}
+		p.next()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != tokRParen {
+			return nil, fmt.Errorf("glob: expected ')' in expression %q", p.input)
+		}
+		p.next()
+		return n, nil
+	case tokPattern:
+		pattern := p.tokText
+		if pattern == "" {
+			return nil, fmt.Errorf("glob: expected a pattern in expression %q", p.input)
+		}
+		g, err := Compile(pattern, p.separators...)
+		if err != nil {
+			return nil, err
+		}
+		p.leaves = append(p.leaves, pattern)
+		p.next()
+		return &leafNode{glob: g}, nil
+	default:
+		return nil, fmt.Errorf("glob: expected a pattern or '(' in expression %q", p.input)
+	}
+}
@@ -0,0 +1,86 @@
+package glob
+
+import "testing"
+
+func TestCompileExprMatch(t *testing.T) {
+	tests := []struct {
+		expr    string
+		fixture string
+		want    bool
+	}{
+		{"*.go && !vendor/**", "main.go", true},
+		{"*.go && !vendor/**", "vendor/lib.go", false},
+		{"*.go || *.py", "script.py", true},
+		{"(*.go || *.py) && !*_test.go", "main_test.go", false},
+		{"(*.go || *.py) && !*_test.go", "main.go", true},
+		// Extended-glob and bracket-class syntax embedded in a leaf must
+		// not be shredded by the &&/||/! operator tokenizer.
+		{"@(foo|bar.go) && *.go", "bar.go", true},
+		{"@(foo|bar.go) && *.go", "baz.go", false},
+		{"[!a-z]*.go", "9foo.go", true},
+		{"[!a-z]*.go", "afoo.go", false},
+		// A bare '!' inside a leaf, away from a token boundary, is part of
+		// the pattern rather than a NOT operator.
+		{"CHANGELOG!.md", "CHANGELOG!.md", true},
+		{"CHANGELOG!.md", "CHANGELOG.md", false},
+	}
+	for _, tt := range tests {
+		e, err := CompileExpr(tt.expr, '/')
+		if err != nil {
+			t.Errorf("CompileExpr(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got := e.Match(tt.fixture); got != tt.want {
+			t.Errorf("CompileExpr(%q).Match(%q) = %v, want %v", tt.expr, tt.fixture, got, tt.want)
+		}
+	}
+}
+
+func TestCompileExprLeaves(t *testing.T) {
+	e, err := CompileExpr("@(foo|bar) && *.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"@(foo|bar)", "*.go"}
+	got := e.Strings()
+	if len(got) != len(want) {
+		t.Fatalf("Strings() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Strings()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCompileExprSyntaxError(t *testing.T) {
+	if _, err := CompileExpr("*.go &&"); err == nil {
+		t.Error("CompileExpr with a dangling operator: want error, got nil")
+	}
+}
+
+// A bare, non-"@"-prefixed parenthesised leaf containing a '|' is
+// indistinguishable from a parenthesised sub-expression around a literal
+// pattern: per the documented grammar a lone '|' outside "(...)" isn't a
+// meta character, so "(foo|bar)" would otherwise silently compile to a
+// literal match for the 7-byte string "foo|bar" rather than the
+// alternation its syntax suggests. CompileExpr rejects it instead.
+func TestCompileExprRejectsBareExtGlob(t *testing.T) {
+	tests := []string{
+		"(foo|bar)",
+		"(foo|bar) && *.go",
+	}
+	for _, expr := range tests {
+		if _, err := CompileExpr(expr); err == nil {
+			t.Errorf("CompileExpr(%q): want error guiding toward \"@(...)\", got nil", expr)
+		}
+	}
+	// A single leaf with no '|' is unambiguous and still allowed.
+	if _, err := CompileExpr("(*.go)"); err != nil {
+		t.Errorf("CompileExpr(%q): %v", "(*.go)", err)
+	}
+	// A genuine boolean grouping is unaffected.
+	if _, err := CompileExpr("(*.go || *.py) && !*_test.go"); err != nil {
+		t.Errorf("CompileExpr(%q): %v", "(*.go || *.py) && !*_test.go", err)
+	}
+}
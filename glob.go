@@ -2,19 +2,32 @@ package glob
 
 import (
 	"fmt"
+	"io"
 	"regexp"
-
-	"github.com/glenn-brown/golang-pkg-pcre/src/pkg/pcre"
+	"strings"
 
 	"github.com/pachyderm/glob/compiler"
 	"github.com/pachyderm/glob/syntax"
 	"github.com/pachyderm/glob/syntax/ast"
 )
 
+// MaxReaderLookahead bounds how many runes MatchReader and FindReaderIndex
+// buffer at a time for engines that have no native reader-based matcher. It
+// has no effect on engines, such as the stdlib regexp engine, that
+// implement reader-based matching directly. CompileOpts with
+// WithReaderBuffer overrides this default for a single Glob.
+var MaxReaderLookahead = 64 * 1024
+
 // Glob represents compiled glob pattern.
 type Glob struct {
-	r *regexp.Regexp
-	p *pcre.Regexp
+	re Regexp
+	// prefix is the longest prefix of the source pattern containing no
+	// glob meta characters. It lets CompilePath's Walk prune directory
+	// traversal and lets Set bucket patterns by shared prefix.
+	prefix string
+	// readerBuf overrides MaxReaderLookahead for this Glob when positive;
+	// 0 means "use the package-level default".
+	readerBuf int
 }
 
 // Compile creates Glob for given pattern and strings (if any present after pattern) as separators.
@@ -54,7 +67,16 @@ type Glob struct {
 //        `?(` { `|` pattern } `)`
 //                    capture zero or one of of pipe-separated subpatterns
 //
+// The regex engine used to run the compiled pattern is chosen from the
+// registered Engines based on the features the pattern requires; see
+// RegisterEngine. For diagnostics in place of the debug output earlier
+// versions printed unconditionally, see SetLogger, CompileOpts and
+// WithTrace.
 func Compile(pattern string, separators ...rune) (*Glob, error) {
+	return compile(pattern, separators, nil, 0)
+}
+
+func compile(pattern string, separators []rune, trace func(pattern, regex string, engine ast.CompilerKind), readerBuf int) (*Glob, error) {
 	tree, compilerToUse, err := syntax.Parse(pattern)
 	if err != nil {
 		return nil, err
@@ -64,24 +86,73 @@ func Compile(pattern string, separators ...rune) (*Glob, error) {
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println("pattern:", pattern)
-	fmt.Println("regexp:", regex, compilerToUse)
 
-	switch compilerToUse {
-	case ast.Regexp:
-		r, err := regexp.Compile(regex)
-		if err != nil {
-			return nil, err
+	if trace != nil {
+		trace(pattern, regex, compilerToUse)
+	}
+	if logger != nil {
+		logger.Printf("glob: pattern %q compiled to %q (%v)", pattern, regex, compilerToUse)
+	}
+
+	name := engineName(compilerToUse)
+	engine, ok := engines[name]
+	if !ok {
+		if name == "pcre" {
+			return nil, fmt.Errorf("glob: pattern %q uses %s, which needs the %q engine that is not linked into this binary (build with -tags %s)", pattern, pcreConstruct(regex), name, name)
 		}
-		return &Glob{r: r}, nil
+		return nil, fmt.Errorf("glob: pattern %q requires the %q engine, which is not linked into this binary (build with -tags %s)", pattern, name, name)
+	}
+
+	re, err := engine.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	return &Glob{re: re, prefix: literalPrefix(pattern), readerBuf: readerBuf}, nil
+}
+
+// literalPrefix returns the longest prefix of pattern containing no glob
+// meta characters.
+func literalPrefix(pattern string) string {
+	i := 0
+	for i < len(pattern) && pattern[i] != '\\' && !syntax.Special(pattern[i]) {
+		i++
+	}
+	return pattern[:i]
+}
+
+// pcreConstruct names the regex feature, in the compiled regex, that the
+// PCRE engine is needed for, so an unlinked-engine error can point at what
+// in the pattern triggered it instead of just naming the engine.
+func pcreConstruct(regex string) string {
+	switch {
+	case reBackref.MatchString(regex):
+		return "a backreference"
+	case strings.Contains(regex, "(?="), strings.Contains(regex, "(?!"):
+		return "a lookahead"
+	case strings.Contains(regex, "(?<="), strings.Contains(regex, "(?<!"):
+		return "a lookbehind"
+	case strings.Contains(regex, "(?>"):
+		return "an atomic group"
+	case rePossessive.MatchString(regex):
+		return "a possessive quantifier"
+	default:
+		return "an extended-glob capture feature"
+	}
+}
+
+var (
+	reBackref    = regexp.MustCompile(`\\[1-9]`)
+	rePossessive = regexp.MustCompile(`[*+?}]\+`)
+)
+
+func engineName(kind ast.CompilerKind) string {
+	switch kind {
+	case ast.Regexp:
+		return "regexp"
 	case ast.PCRE:
-		p, pcreErr := pcre.Compile(regex, 0)
-		if pcreErr != nil {
-			return nil, fmt.Errorf(pcreErr.String())
-		}
-		return &Glob{p: &p}, nil
+		return "pcre"
 	default:
-		return nil, fmt.Errorf("Unrecognized compiler: %v", compilerToUse)
+		return fmt.Sprintf("%v", kind)
 	}
 }
 
@@ -95,26 +166,84 @@ func MustCompile(pattern string, separators ...rune) *Glob {
 }
 
 func (g *Glob) Match(fixture string) bool {
-	if g.r != nil {
-		return g.r.MatchString(fixture)
-	}
-	m := g.p.MatcherString(fixture, 0)
-	return m.MatchString(fixture, 0)
+	return g.re.MatchString(fixture)
 }
 
 func (g *Glob) Capture(fixture string) []string {
-	if g.r != nil {
-		return g.r.FindStringSubmatch(fixture)
-	}
-	m := g.p.MatcherString(fixture, 0)
-	num := m.Groups()
-	groups := make([]string, 0, num)
-	if m.MatchString(fixture, 0) {
-		for i := 0; i <= num; i++ {
-			groups = append(groups, m.GroupString(i))
+	return g.re.FindStringSubmatch(fixture)
+}
+
+// readerMatcher is implemented by engines, such as the stdlib regexp
+// engine, that can match directly against an io.RuneReader.
+type readerMatcher interface {
+	MatchReader(r io.RuneReader) bool
+	FindReaderIndex(r io.RuneReader) []int
+}
+
+// bufSize returns the rune buffer size MatchReader and FindReaderIndex use
+// for this Glob: readerBuf if WithReaderBuffer set one, else the
+// package-level MaxReaderLookahead default.
+func (g *Glob) bufSize() int {
+	if g.readerBuf > 0 {
+		return g.readerBuf
+	}
+	return MaxReaderLookahead
+}
+
+// MatchReader reports whether the text read from r matches g. Engines that
+// implement readerMatcher stream runes directly; others buffer up to
+// bufSize runes and match against the resulting string.
+func (g *Glob) MatchReader(r io.RuneReader) bool {
+	if rm, ok := g.re.(readerMatcher); ok {
+		return rm.MatchReader(r)
+	}
+	s, _ := readRunes(r, g.bufSize())
+	return g.Match(s)
+}
+
+// FindReaderIndex is like MatchReader but returns the leftmost match's byte
+// offsets into the runes read from r, or nil if there is no match. err is
+// non-nil only if reading from r fails.
+func (g *Glob) FindReaderIndex(r io.RuneReader) (loc []int, err error) {
+	if rm, ok := g.re.(readerMatcher); ok {
+		return rm.FindReaderIndex(r), nil
+	}
+	s, err := readRunes(r, g.bufSize())
+	if err != nil {
+		return nil, err
+	}
+	if bf, ok := g.re.(interface{ FindIndex(b []byte) []int }); ok {
+		return bf.FindIndex([]byte(s)), nil
+	}
+	m := g.re.FindStringSubmatch(s)
+	if m == nil {
+		return nil, nil
+	}
+	// The minimal Regexp interface (MatchString/FindStringSubmatch/
+	// NumSubexp) has no index-aware method, so an engine that only
+	// implements that much can't report a precise byte offset here.
+	// Approximate it as the first occurrence of the matched text instead
+	// of claiming the match spans the entire buffered read.
+	i := strings.Index(s, m[0])
+	if i < 0 {
+		return nil, nil
+	}
+	return []int{i, i + len(m[0])}, nil
+}
+
+func readRunes(r io.RuneReader, max int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < max; i++ {
+		ru, _, err := r.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
 		}
+		sb.WriteRune(ru)
 	}
-	return groups
+	return sb.String(), nil
 }
 
 // QuoteMeta returns a string that quotes all glob pattern meta characters
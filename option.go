@@ -0,0 +1,58 @@
+package glob
+
+import (
+	"log"
+
+	"github.com/pachyderm/glob/syntax/ast"
+)
+
+// CompileOption configures a Compile call made through CompileOpts. It
+// exists so that features like tracing and reader buffer sizing, or future
+// ones such as engine selection, can be added without growing Compile's
+// parameter list.
+type CompileOption func(*compileOptions)
+
+type compileOptions struct {
+	separators []rune
+	trace      func(pattern, regex string, engine ast.CompilerKind)
+	readerBuf  int
+}
+
+// WithSeparators sets the separator runes used to delimit `*` and `?`,
+// equivalent to the trailing arguments Compile accepts positionally.
+func WithSeparators(separators ...rune) CompileOption {
+	return func(o *compileOptions) { o.separators = separators }
+}
+
+// WithTrace installs a callback invoked with the compiled regex fragment
+// and the engine chosen for a pattern, in place of the debug output
+// Compile used to print unconditionally.
+func WithTrace(fn func(pattern, regex string, engine ast.CompilerKind)) CompileOption {
+	return func(o *compileOptions) { o.trace = fn }
+}
+
+// WithReaderBuffer overrides MaxReaderLookahead for this Glob alone,
+// bounding how many runes MatchReader and FindReaderIndex buffer when the
+// chosen engine has no native reader-based matcher.
+func WithReaderBuffer(n int) CompileOption {
+	return func(o *compileOptions) { o.readerBuf = n }
+}
+
+// CompileOpts is like Compile but configured with CompileOption values
+// instead of positional separators, for callers that also want tracing or
+// other opt-in behaviour.
+func CompileOpts(pattern string, opts ...CompileOption) (*Glob, error) {
+	var o compileOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return compile(pattern, o.separators, o.trace, o.readerBuf)
+}
+
+var logger *log.Logger
+
+// SetLogger installs logger as the destination for package-wide compile
+// diagnostics, or disables them when logger is nil.
+func SetLogger(l *log.Logger) {
+	logger = l
+}
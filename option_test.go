@@ -0,0 +1,64 @@
+package glob
+
+import (
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/glob/syntax/ast"
+)
+
+func TestCompileOptsWithSeparators(t *testing.T) {
+	g, err := CompileOpts("a*b", WithSeparators('/'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Compile("a*b", '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []string{"a/b", "acb", "a/c/b"}
+	for _, fixture := range tests {
+		if got, w := g.Match(fixture), want.Match(fixture); got != w {
+			t.Errorf("Match(%q) = %v, want %v", fixture, got, w)
+		}
+	}
+}
+
+func TestWithTrace(t *testing.T) {
+	var gotPattern, gotRegex string
+	var gotKind ast.CompilerKind
+	_, err := CompileOpts("*.go", WithTrace(func(pattern, regex string, engine ast.CompilerKind) {
+		gotPattern, gotRegex, gotKind = pattern, regex, engine
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPattern != "*.go" {
+		t.Errorf("trace pattern = %q, want %q", gotPattern, "*.go")
+	}
+	if gotRegex == "" {
+		t.Error("trace regex = \"\", want the compiled regex fragment")
+	}
+	if gotKind != ast.Regexp {
+		t.Errorf("trace engine = %v, want %v", gotKind, ast.Regexp)
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(nil)
+
+	SetLogger(nil)
+	if _, err := Compile("*.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf strings.Builder
+	SetLogger(log.New(&buf, "", 0))
+	if _, err := Compile("*.go"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() == 0 {
+		t.Error("SetLogger: no output written to the installed logger")
+	}
+}
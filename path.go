@@ -0,0 +1,72 @@
+package glob
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// CompilePath compiles pattern as a doublestar-compatible path glob: `/` is
+// fixed as the only separator, so `?`, `*` and character classes never
+// cross it, and `**` matches zero or more whole path segments. A leading
+// `**/` is optional and a trailing `/**` matches everything below, so
+// `a/**/b` matches `a/b`, `a/x/b` and `a/x/y/b`.
+func CompilePath(pattern string) (*Glob, error) {
+	g, err := Compile(expandDoubleStar(pattern), '/')
+	if err != nil {
+		return nil, err
+	}
+	// literalPrefix ran on the expandDoubleStar output, whose inserted
+	// `{...}` alternation swallows the separator that anchored the
+	// prefix (e.g. "a/**/b" becomes "a{/**/,/}b"). Recompute it from the
+	// pre-expansion pattern so Walk still prunes into "a", not ".".
+	g.prefix = literalPrefix(pattern)
+	return g, nil
+}
+
+// expandDoubleStar rewrites the doublestar zero-match cases (a leading
+// `**/`, a trailing `/**`, and an interior `/**/`) into glob alternations,
+// since the compiler already treats `**` as "any sequence of characters"
+// but has no notion of the empty match doublestar users expect.
+func expandDoubleStar(pattern string) string {
+	if strings.HasPrefix(pattern, "**/") {
+		pattern = "{**/,}" + pattern[len("**/"):]
+	}
+	if strings.HasSuffix(pattern, "/**") {
+		pattern = pattern[:len(pattern)-len("/**")] + "{/**,}"
+	}
+	return strings.ReplaceAll(pattern, "/**/", "{/**/,/}")
+}
+
+// Walk calls fn for every path in fsys that matches g, in the order given
+// by fs.WalkDir, stopping early and returning the error if fn returns one.
+// As with fs.WalkDir, returning fs.SkipDir from fn skips the rest of the
+// containing directory and fs.SkipAll stops the walk entirely. Directories
+// that fall outside the pattern's literal prefix are pruned rather than
+// descended into; a directory matched by g itself — including the pruning
+// root, e.g. CompilePath("vendor/**") also matching the bare "vendor" entry
+// — is still reported via fn, the same as a matching file, and then
+// descended into as usual.
+func (g *Glob) Walk(fsys fs.FS, fn func(path string) error) error {
+	root := "."
+	if i := strings.LastIndexByte(g.prefix, '/'); i >= 0 {
+		root = g.prefix[:i]
+	}
+	return fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && g.prefix != "" && !strings.HasPrefix(g.prefix, p+"/") && !strings.HasPrefix(p, g.prefix) {
+				return fs.SkipDir
+			}
+			if g.Match(p) {
+				return fn(p)
+			}
+			return nil
+		}
+		if g.Match(p) {
+			return fn(p)
+		}
+		return nil
+	})
+}
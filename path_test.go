@@ -0,0 +1,121 @@
+package glob
+
+import (
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCompilePathLiteralPrefix(t *testing.T) {
+	// expandDoubleStar rewrites "**" into a `{...}` alternation before the
+	// pattern reaches literalPrefix; the prefix used by Walk must still be
+	// computed as if that rewrite never happened, or the separator that
+	// anchors it is lost.
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"a/**/b", "a/"},
+		{"logs/**", "logs/"},
+		{"src/**/main.go", "src/"},
+	}
+	for _, tt := range tests {
+		g, err := CompilePath(tt.pattern)
+		if err != nil {
+			t.Fatalf("CompilePath(%q): %v", tt.pattern, err)
+		}
+		if g.prefix != tt.want {
+			t.Errorf("CompilePath(%q).prefix = %q, want %q", tt.pattern, g.prefix, tt.want)
+		}
+	}
+}
+
+func TestGlobWalk(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/b/file.go":   &fstest.MapFile{},
+		"a/x/b/file.go": &fstest.MapFile{},
+		"other/file.go": &fstest.MapFile{},
+	}
+	g, err := CompilePath("a/**/b/**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	if err := g.Walk(fsys, func(p string) error {
+		got = append(got, p)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	// The trailing "/**" also matches zero segments, so the directories
+	// "a/b" and "a/x/b" match too and Walk reports them.
+	want := []string{"a/b", "a/b/file.go", "a/x/b", "a/x/b/file.go"}
+	if len(got) != len(want) {
+		t.Fatalf("Walk visited %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Walk visited[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGlobWalkMatchesDirectory(t *testing.T) {
+	fsys := fstest.MapFS{
+		"vendor/lib.go": &fstest.MapFile{},
+		"src/main.go":   &fstest.MapFile{},
+	}
+	g, err := CompilePath("vendor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	if err := g.Walk(fsys, func(p string) error {
+		got = append(got, p)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, p := range got {
+		if p == "vendor" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Walk(%v) = %v, want it to include the matching directory %q", fsys, got, "vendor")
+	}
+}
+
+// CompilePath("X/**") expands the trailing "/**" into an alternation that
+// also matches the bare "X" entry (the same zero-match rule that lets
+// CompilePath("a/**/b") match "a/b"). That entry is also Walk's pruning
+// root, so the match check must not special-case it away.
+func TestGlobWalkMatchesRoot(t *testing.T) {
+	fsys := fstest.MapFS{
+		"vendor/lib.go": &fstest.MapFile{},
+		"src/main.go":   &fstest.MapFile{},
+	}
+	g, err := CompilePath("vendor/**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	if err := g.Walk(fsys, func(p string) error {
+		got = append(got, p)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	want := []string{"vendor", "vendor/lib.go"}
+	if len(got) != len(want) {
+		t.Fatalf("Walk visited %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Walk visited[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package glob
+
+import "strings"
+
+// PatternSet matches against a whitespace-separated list of glob patterns,
+// such as "*.go *.py !*_test.go !vendor/**", where a leading `!` negates a
+// term. Terms are tested in declaration order and the first one to match —
+// positive or negative — decides the result.
+type PatternSet struct {
+	terms []patternTerm
+}
+
+type patternTerm struct {
+	glob   *Glob
+	negate bool
+}
+
+// CompilePatterns splits expr on whitespace and compiles each field with
+// Compile using separators, treating a leading `!` as negation.
+func CompilePatterns(expr string, separators ...rune) (*PatternSet, error) {
+	fields := strings.Fields(expr)
+	terms := make([]patternTerm, 0, len(fields))
+	for _, f := range fields {
+		negate := strings.HasPrefix(f, "!")
+		if negate {
+			f = f[1:]
+		}
+		g, err := Compile(f, separators...)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, patternTerm{glob: g, negate: negate})
+	}
+	return &PatternSet{terms: terms}, nil
+}
+
+// Match reports whether fixture matches the pattern set: the first term, in
+// declaration order, whose glob matches fixture decides the result.
+func (s *PatternSet) Match(fixture string) bool {
+	for _, t := range s.terms {
+		if t.glob.Match(fixture) {
+			return !t.negate
+		}
+	}
+	return false
+}
+
+// MatchAny reports whether fixture matches any term in the set, positive or
+// negative, ignoring declaration order.
+func (s *PatternSet) MatchAny(fixture string) bool {
+	for _, t := range s.terms {
+		if t.glob.Match(fixture) {
+			return true
+		}
+	}
+	return false
+}
+
+// Capture returns the capture groups of the first matching positive
+// pattern, in declaration order, or nil if fixture does not match.
+func (s *PatternSet) Capture(fixture string) []string {
+	for _, t := range s.terms {
+		if !t.glob.Match(fixture) {
+			continue
+		}
+		if t.negate {
+			return nil
+		}
+		return t.glob.Capture(fixture)
+	}
+	return nil
+}
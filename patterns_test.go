@@ -0,0 +1,59 @@
+package glob
+
+import "testing"
+
+func TestPatternSetMatch(t *testing.T) {
+	s, err := CompilePatterns("*.go *.py !*_test.go !vendor/**", '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		fixture string
+		want    bool
+	}{
+		{"main.go", true},
+		{"script.py", true},
+		// "*.go" is declared before "!*_test.go", so it matches first.
+		{"main_test.go", true},
+		{"vendor/lib.go", false},
+		{"README.md", false},
+	}
+	for _, tt := range tests {
+		if got := s.Match(tt.fixture); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.fixture, got, tt.want)
+		}
+	}
+}
+
+func TestPatternSetMatchAny(t *testing.T) {
+	s, err := CompilePatterns("*.go !vendor/**", '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		fixture string
+		want    bool
+	}{
+		{"main.go", true},
+		{"vendor/lib.go", true},
+		{"README.md", false},
+	}
+	for _, tt := range tests {
+		if got := s.MatchAny(tt.fixture); got != tt.want {
+			t.Errorf("MatchAny(%q) = %v, want %v", tt.fixture, got, tt.want)
+		}
+	}
+}
+
+func TestPatternSetCapture(t *testing.T) {
+	s, err := CompilePatterns("(*.go) !vendor/**", '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Capture("vendor/lib.go"); got != nil {
+		t.Errorf("Capture(%q) = %v, want nil (negated term matches first)", "vendor/lib.go", got)
+	}
+	if got := s.Capture("main.go"); got == nil {
+		t.Errorf("Capture(%q) = nil, want a match", "main.go")
+	}
+}
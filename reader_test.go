@@ -0,0 +1,80 @@
+package glob
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGlobMatchReader(t *testing.T) {
+	g, err := Compile("*.go", '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		fixture string
+		want    bool
+	}{
+		{"main.go", true},
+		{"main.py", false},
+	}
+	for _, tt := range tests {
+		if got := g.MatchReader(strings.NewReader(tt.fixture)); got != tt.want {
+			t.Errorf("MatchReader(%q) = %v, want %v", tt.fixture, got, tt.want)
+		}
+	}
+}
+
+func TestGlobFindReaderIndex(t *testing.T) {
+	g, err := Compile("*bar*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture := "foobarbaz"
+	loc, err := g.FindReaderIndex(strings.NewReader(fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc == nil {
+		t.Fatalf("FindReaderIndex(%q) = nil, want a match", fixture)
+	}
+	if got := fixture[loc[0]:loc[1]]; got != fixture {
+		t.Errorf("FindReaderIndex(%q) spans %q, want the whole fixture %q", fixture, got, fixture)
+	}
+}
+
+func TestGlobFindReaderIndexNoMatch(t *testing.T) {
+	g, err := Compile("zzz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := g.FindReaderIndex(strings.NewReader("foobar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc != nil {
+		t.Errorf("FindReaderIndex with no match = %v, want nil", loc)
+	}
+}
+
+// WithReaderBuffer overrides MaxReaderLookahead per-Glob: a buffer too
+// small to hold the fixture truncates what MatchReader sees. The buffer only
+// applies to engines without native reader matching, so the stdlib Regexp is
+// wrapped to hide its MatchReader/FindReaderIndex methods.
+func TestWithReaderBuffer(t *testing.T) {
+	g, err := CompileOpts("hello", WithReaderBuffer(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.re = struct{ Regexp }{g.re}
+	if g.MatchReader(strings.NewReader("hello")) {
+		t.Error("MatchReader with a 3-rune buffer matched a 5-rune pattern; want the buffer to truncate the read")
+	}
+	full, err := CompileOpts("hello", WithReaderBuffer(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	full.re = struct{ Regexp }{full.re}
+	if !full.MatchReader(strings.NewReader("hello")) {
+		t.Error("MatchReader with a 64-rune buffer: want it to match")
+	}
+}
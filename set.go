@@ -0,0 +1,158 @@
+package glob
+
+import (
+	"sort"
+	"strings"
+)
+
+// Set is a matcher over many glob patterns that share literal prefixes,
+// such as the hundreds of dockerignore-style rules an application might
+// carry. Patterns are indexed by literal (non-meta) prefix into a trie, so
+// Match and MatchAll can discard whole subtrees of patterns after a single
+// prefix comparison instead of testing every pattern. Patterns bucketed
+// into the same node (sharing a prefix, most commonly the empty one) are
+// additionally combined into one alternation Glob, so a fixture that
+// matches none of them is rejected in a single pass instead of one
+// Glob.Match call per pattern.
+type Set struct {
+	root *setNode
+}
+
+type setEntry struct {
+	pattern string
+	glob    *Glob
+	idx     int
+}
+
+type setNode struct {
+	edge     string
+	entries  []setEntry
+	children []*setNode
+	// combined is the alternation of every entries[i].pattern, used to
+	// reject a fixture that matches none of them without testing each one
+	// individually. It is nil while there's 0 or 1 entries, since a linear
+	// scan is already as cheap as the combined check would be.
+	combined *Glob
+}
+
+// NewSet compiles each pattern with Compile using separators and indexes
+// them by literal prefix for sub-linear matching.
+func NewSet(patterns []string, separators ...rune) (*Set, error) {
+	root := &setNode{}
+	for i, pattern := range patterns {
+		g, err := Compile(pattern, separators...)
+		if err != nil {
+			return nil, err
+		}
+		root.insert(g.prefix, setEntry{pattern: pattern, glob: g, idx: i})
+	}
+	if err := root.combine(separators); err != nil {
+		return nil, err
+	}
+	return &Set{root: root}, nil
+}
+
+func (n *setNode) insert(prefix string, e setEntry) {
+	if prefix == "" {
+		for _, c := range n.children {
+			if c.edge == "" {
+				c.entries = append(c.entries, e)
+				return
+			}
+		}
+		n.children = append(n.children, &setNode{entries: []setEntry{e}})
+		return
+	}
+	for _, c := range n.children {
+		l := commonPrefixLen(c.edge, prefix)
+		if l == 0 {
+			continue
+		}
+		if l < len(c.edge) {
+			split := &setNode{edge: c.edge[l:], entries: c.entries, children: c.children}
+			c.edge = c.edge[:l]
+			c.entries = nil
+			c.children = []*setNode{split}
+		}
+		c.insert(prefix[l:], e)
+		return
+	}
+	n.children = append(n.children, &setNode{edge: prefix, entries: []setEntry{e}})
+}
+
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// combine builds each node's combined alternation Glob, recursively.
+func (n *setNode) combine(separators []rune) error {
+	if len(n.entries) > 1 {
+		patterns := make([]string, len(n.entries))
+		for i, e := range n.entries {
+			patterns[i] = e.pattern
+		}
+		g, err := Compile("{"+strings.Join(patterns, ",")+"}", separators...)
+		if err != nil {
+			return err
+		}
+		n.combined = g
+	}
+	for _, c := range n.children {
+		if err := c.combine(separators); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match returns the index, in the order passed to NewSet, of the
+// lowest-indexed pattern that matches fixture, and reports whether any
+// pattern matched.
+func (s *Set) Match(fixture string) (idx int, ok bool) {
+	best := -1
+	s.root.walk(fixture, fixture, func(e setEntry) {
+		if e.glob.Match(fixture) && (best == -1 || e.idx < best) {
+			best = e.idx
+		}
+	})
+	if best == -1 {
+		return 0, false
+	}
+	return best, true
+}
+
+// MatchAll returns the indices, in the order passed to NewSet, of every
+// pattern that matches fixture.
+func (s *Set) MatchAll(fixture string) []int {
+	var idxs []int
+	s.root.walk(fixture, fixture, func(e setEntry) {
+		if e.glob.Match(fixture) {
+			idxs = append(idxs, e.idx)
+		}
+	})
+	sort.Ints(idxs)
+	return idxs
+}
+
+// walk visits every entry whose literal prefix is consistent with fixture,
+// pruning subtrees whose edge the remaining suffix does not start with.
+// fixture is the original, full fixture passed to Match/MatchAll (every
+// entry's Glob is compiled against the whole fixture, not a suffix of it);
+// suffix is what's left of fixture after consuming the edges of n's
+// ancestors, and is what edge-prefix comparisons are made against.
+func (n *setNode) walk(fixture, suffix string, visit func(setEntry)) {
+	if n.combined == nil || n.combined.Match(fixture) {
+		for _, e := range n.entries {
+			visit(e)
+		}
+	}
+	for _, c := range n.children {
+		if strings.HasPrefix(suffix, c.edge) {
+			c.walk(fixture, suffix[len(c.edge):], visit)
+		}
+	}
+}
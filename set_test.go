@@ -0,0 +1,95 @@
+package glob
+
+import "testing"
+
+func TestSetMatch(t *testing.T) {
+	s, err := NewSet([]string{"*.go", "*.md", "vendor/**"}, '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		fixture string
+		wantIdx int
+		wantOk  bool
+	}{
+		{"main.go", 0, true},
+		{"README.md", 1, true},
+		{"vendor/lib.go", 2, true},
+		{"other.txt", 0, false},
+	}
+	for _, tt := range tests {
+		idx, ok := s.Match(tt.fixture)
+		if ok != tt.wantOk {
+			t.Errorf("Match(%q) ok = %v, want %v", tt.fixture, ok, tt.wantOk)
+			continue
+		}
+		if ok && idx != tt.wantIdx {
+			t.Errorf("Match(%q) idx = %d, want %d", tt.fixture, idx, tt.wantIdx)
+		}
+	}
+}
+
+func TestSetMatchAll(t *testing.T) {
+	s, err := NewSet([]string{"*.go", "main.*"}, '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := s.MatchAll("main.go")
+	want := []int{0, 1}
+	if len(got) != len(want) {
+		t.Fatalf("MatchAll(%q) = %v, want %v", "main.go", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("MatchAll(%q)[%d] = %d, want %d", "main.go", i, got[i], want[i])
+		}
+	}
+}
+
+// Two patterns whose literal prefixes diverge below a shared split (e.g.
+// "vendor/a*" and "vendor/b*", sharing "vendor/" then splitting into
+// children "a" and "b") must still match fixtures landing in the second
+// child: walk has to compare each child's edge against the suffix left
+// after consuming its ancestors' edges, not the whole original fixture.
+func TestSetMatchDivergingPrefixes(t *testing.T) {
+	s, err := NewSet([]string{"vendor/a*", "vendor/b*"}, '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		fixture string
+		wantIdx int
+		wantOk  bool
+	}{
+		{"vendor/axyz", 0, true},
+		{"vendor/bxyz", 1, true},
+		{"vendor/cxyz", 0, false},
+	}
+	for _, tt := range tests {
+		idx, ok := s.Match(tt.fixture)
+		if ok != tt.wantOk {
+			t.Errorf("Match(%q) ok = %v, want %v", tt.fixture, ok, tt.wantOk)
+			continue
+		}
+		if ok && idx != tt.wantIdx {
+			t.Errorf("Match(%q) idx = %d, want %d", tt.fixture, idx, tt.wantIdx)
+		}
+	}
+}
+
+// Patterns whose literal prefix is empty (the dominant shape for
+// dockerignore-style sets: leading `*`, `**`, `[`, ...) must all be grouped
+// under the trie's single empty-edge node rather than each spawning its own
+// sibling, or the trie gives no pruning at all for them.
+func TestSetInsertGroupsEmptyPrefix(t *testing.T) {
+	s, err := NewSet([]string{"*.go", "*.py", "*.txt", "*.md", "*.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.root.children) != 1 {
+		t.Fatalf("root.children has %d nodes, want 1 shared empty-prefix node", len(s.root.children))
+	}
+	if got := len(s.root.children[0].entries); got != 5 {
+		t.Fatalf("root.children[0].entries has %d entries, want all 5 patterns", got)
+	}
+}